@@ -0,0 +1,95 @@
+package depsclient
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+)
+
+// ErrCacheMiss is returned when the server answers 304 Not Modified but the
+// Cache no longer holds the body it was conditioned on (e.g. it was evicted
+// or the Client was restarted with an empty cache). It does not mean the
+// resource is missing, so it is kept distinct from ErrNotFound.
+var ErrCacheMiss = errors.New("cached response no longer available")
+
+// Cache is an optional, transparent conditional-request cache for Client.
+// Most endpoints in this API (fractions list, map, admins, leaders, ...)
+// change infrequently per serverID, so a GET response can be reused as long
+// as the server keeps returning the ETag we last saw via If-None-Match.
+type Cache interface {
+	// Get returns the ETag and decoded body previously stored for key, if any.
+	Get(key string) (etag string, body any, ok bool)
+	// Set stores the ETag and decoded body for key, replacing any previous entry.
+	Set(key, etag string, body any)
+}
+
+// WithCache installs a Cache on the Client. Once set, GET requests carry an
+// If-None-Match header built from the last ETag seen for that request, and a
+// 304 Not Modified response is transparently resolved to the cached body.
+func WithCache(cache Cache) Option {
+	return func(cfg *clientConfig) error {
+		cfg.cache = cache
+		return nil
+	}
+}
+
+type cacheEntry struct {
+	key  string
+	etag string
+	body any
+}
+
+// memoryCache is a bounded, in-process LRU implementation of Cache, safe for
+// concurrent use by multiple goroutines sharing the same Client.
+type memoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+// NewMemoryCache returns an in-memory LRU Cache holding at most maxEntries
+// entries. A non-positive maxEntries disables eviction.
+func NewMemoryCache(maxEntries int) Cache {
+	return &memoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (c *memoryCache) Get(key string) (string, any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return "", nil, false
+	}
+	c.ll.MoveToFront(el)
+	entry := el.Value.(*cacheEntry)
+	return entry.etag, entry.body, true
+}
+
+func (c *memoryCache) Set(key, etag string, body any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*cacheEntry).etag = etag
+		el.Value.(*cacheEntry).body = body
+		return
+	}
+
+	el := c.ll.PushFront(&cacheEntry{key: key, etag: etag, body: body})
+	c.items[key] = el
+
+	if c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*cacheEntry).key)
+		}
+	}
+}
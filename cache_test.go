@@ -0,0 +1,71 @@
+package depsclient
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestMemoryCacheGetSetRoundTrip(t *testing.T) {
+	c := NewMemoryCache(10)
+
+	if _, _, ok := c.Get("missing"); ok {
+		t.Fatal("Get on empty cache returned ok=true")
+	}
+
+	c.Set("k", "etag-1", "body-1")
+	etag, body, ok := c.Get("k")
+	if !ok || etag != "etag-1" || body != "body-1" {
+		t.Fatalf("Get(%q) = (%q, %v, %v), want (%q, %q, true)", "k", etag, body, ok, "etag-1", "body-1")
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewMemoryCache(2)
+
+	c.Set("a", "etag-a", "body-a")
+	c.Set("b", "etag-b", "body-b")
+	// Touch "a" so "b" becomes the least recently used entry.
+	c.Get("a")
+	c.Set("c", "etag-c", "body-c")
+
+	if _, _, ok := c.Get("b"); ok {
+		t.Error("expected \"b\" to be evicted, but it is still cached")
+	}
+	if _, _, ok := c.Get("a"); !ok {
+		t.Error("expected \"a\" to still be cached")
+	}
+	if _, _, ok := c.Get("c"); !ok {
+		t.Error("expected \"c\" to still be cached")
+	}
+}
+
+func TestMemoryCacheConcurrentAccess(t *testing.T) {
+	c := NewMemoryCache(50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := "k"
+			c.Set(key, "etag", i)
+			c.Get(key)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestCacheKey(t *testing.T) {
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{Scheme: "https", Host: "api.depscian.tech", Path: "/v2/fractions", RawQuery: "server_id=1"},
+	}
+
+	got := cacheKey(req)
+	want := "GET https://api.depscian.tech/v2/fractions?server_id=1"
+	if got != want {
+		t.Errorf("cacheKey() = %q, want %q", got, want)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	apiclient "go.depscian.tech/internal/client"
 )
 
@@ -17,28 +19,60 @@ const (
 	apiKeyHeader   = "X-API-Key"
 )
 
+// unpackResponse decodes a generated apiclient response into the typed body
+// plus a *Response carrying the HTTP-level metadata. The *Response is built
+// from httpResponse regardless of status code, so callers can still inspect
+// it (request ID, status, headers, ...) when err reports a non-2xx status.
+// When the call itself failed (err passed in is non-nil) the *Response is nil.
+//
+// When c has a Cache installed, a fresh 2xx body is stored against the
+// request's cache key, and a 304 Not Modified is resolved back to the body
+// stored on a previous call instead of being treated as an error.
 func unpackResponse[T any](
+	c *Client,
 	responseBody *T,
+	rawBody []byte,
 	httpResponse *http.Response,
 	err error,
-) (*T, error) {
+) (*T, *Response, error) {
 	if err != nil {
-		return nil, fmt.Errorf("client execution error: %w", err)
+		return nil, nil, fmt.Errorf("client execution error: %w", err)
+	}
+
+	resp := buildResponse(httpResponse)
+
+	if httpResponse.StatusCode == http.StatusNotModified {
+		if c.cache != nil {
+			if _, cached, ok := c.cache.Get(cacheKey(httpResponse.Request)); ok {
+				if body, ok := cached.(*T); ok {
+					return body, resp, nil
+				}
+			}
+		}
+		return nil, resp, ErrCacheMiss
 	}
 
 	if httpResponse.StatusCode == http.StatusNotFound {
-		return nil, ErrNotFound
+		return nil, resp, ErrNotFound
 	}
 
 	if httpResponse.StatusCode < 200 || httpResponse.StatusCode >= 300 {
-		return nil, fmt.Errorf("api error: status %s", httpResponse.Status)
+		return nil, resp, newAPIError(httpResponse, rawBody)
 	}
 
 	if responseBody == nil {
-		return nil, ErrNotFound
+		return nil, resp, ErrNotFound
+	}
+
+	if c.cache != nil && resp.ETag != "" && httpResponse.Request != nil && httpResponse.Request.Method == http.MethodGet {
+		c.cache.Set(cacheKey(httpResponse.Request), resp.ETag, responseBody)
 	}
 
-	return responseBody, nil
+	return responseBody, resp, nil
+}
+
+func cacheKey(req *http.Request) string {
+	return req.Method + " " + req.URL.String()
 }
 
 type service struct {
@@ -48,6 +82,7 @@ type service struct {
 type Client struct {
 	internalClient *apiclient.ClientWithResponses
 	common         service
+	cache          Cache
 
 	Admins     *AdminsService
 	Families   *FamiliesService
@@ -61,35 +96,57 @@ type Client struct {
 	Status     *StatusService
 }
 
-type Option func(*http.Client, *string) error
+// clientConfig accumulates everything an Option can configure before the
+// Client and its underlying generated client are built.
+type clientConfig struct {
+	httpClient  *http.Client
+	baseURL     string
+	cache       Cache
+	retryPolicy *RetryPolicy
+	rateLimiter *rate.Limiter
+	middleware  []Middleware
+}
+
+type Option func(*clientConfig) error
 
 func NewClient(apiKey string, opts ...Option) (*Client, error) {
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
+	cfg := &clientConfig{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		baseURL: defaultBaseURL,
 	}
-	baseURL := defaultBaseURL
 
 	for _, opt := range opts {
-		if err := opt(httpClient, &baseURL); err != nil {
+		if err := opt(cfg); err != nil {
 			return nil, fmt.Errorf("failed to apply option: %w", err)
 		}
 	}
 
-	authInterceptor := func(ctx context.Context, req *http.Request) error {
-		req.Header.Set(apiKeyHeader, apiKey)
+	cfg.httpClient.Transport = buildTransport(cfg, apiKey)
+
+	c := &Client{cache: cfg.cache}
+
+	cacheInterceptor := func(ctx context.Context, req *http.Request) error {
+		if c.cache == nil || req.Method != http.MethodGet {
+			return nil
+		}
+		if etag, _, ok := c.cache.Get(cacheKey(req)); ok && etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
 		return nil
 	}
 
 	generatedClient, err := apiclient.NewClientWithResponses(
-		baseURL,
-		apiclient.WithHTTPClient(httpClient),
-		apiclient.WithRequestEditorFn(authInterceptor),
+		cfg.baseURL,
+		apiclient.WithHTTPClient(cfg.httpClient),
+		apiclient.WithRequestEditorFn(cacheInterceptor),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
 
-	c := &Client{internalClient: generatedClient}
+	c.internalClient = generatedClient
 	c.common.client = c
 	c.Admins = (*AdminsService)(&c.common)
 	c.Families = (*FamiliesService)(&c.common)
@@ -105,23 +162,52 @@ func NewClient(apiKey string, opts ...Option) (*Client, error) {
 	return c, nil
 }
 
+// buildTransport assembles the http.RoundTripper chain NewClient installs on
+// cfg.httpClient: rate limiting and retry closest to the wire, then the
+// middleware chain (apiKey injection first, followed by anything added via
+// WithMiddleware) wrapping that. Split out from NewClient so the wiring can
+// be exercised directly in tests without constructing a full Client.
+func buildTransport(cfg *clientConfig, apiKey string) http.RoundTripper {
+	transport := cfg.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if cfg.rateLimiter != nil {
+		transport = &rateLimitTripper{next: transport, limiter: cfg.rateLimiter}
+	}
+	if cfg.retryPolicy != nil {
+		transport = &retryTripper{next: transport, policy: *cfg.retryPolicy}
+	}
+
+	middlewares := append([]Middleware{apiKeyMiddleware(apiKey)}, cfg.middleware...)
+	for _, mw := range middlewares {
+		transport = mw(transport)
+	}
+	return transport
+}
+
 func WithBaseURL(url string) Option {
-	return func(_ *http.Client, baseURL *string) error {
-		*baseURL = url
+	return func(cfg *clientConfig) error {
+		cfg.baseURL = url
 		return nil
 	}
 }
 
 func WithTimeout(timeout time.Duration) Option {
-	return func(c *http.Client, _ *string) error {
-		c.Timeout = timeout
+	return func(cfg *clientConfig) error {
+		cfg.httpClient.Timeout = timeout
 		return nil
 	}
 }
 
 func WithHTTPClient(customClient *http.Client) Option {
-	return func(c *http.Client, _ *string) error {
-		*c = *customClient
+	return func(cfg *clientConfig) error {
+		// Copy rather than alias: NewClient goes on to set Transport on
+		// cfg.httpClient (to install the retry/rate-limit/middleware chain),
+		// and must never mutate an *http.Client the caller owns and may
+		// still be using elsewhere.
+		cloned := *customClient
+		cfg.httpClient = &cloned
 		return nil
 	}
 }
@@ -129,135 +215,200 @@ func WithHTTPClient(customClient *http.Client) Option {
 type AdminsService service
 
 func (s *AdminsService) Get(ctx context.Context, serverID int) (*apiclient.AdminsResponse, error) {
+	body, _, err := s.GetWithResponse(ctx, serverID)
+	return body, err
+}
+
+func (s *AdminsService) GetWithResponse(ctx context.Context, serverID int) (*apiclient.AdminsResponse, *Response, error) {
 	params := &apiclient.GetServerAdminsV2AdminsGetParams{ServerId: serverID}
 	resp, err := s.client.internalClient.GetServerAdminsV2AdminsGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 type FamiliesService service
 
 func (s *FamiliesService) List(ctx context.Context, serverID int) (*apiclient.FamilyListResponse, error) {
+	body, _, err := s.ListWithResponse(ctx, serverID)
+	return body, err
+}
+
+func (s *FamiliesService) ListWithResponse(ctx context.Context, serverID int) (*apiclient.FamilyListResponse, *Response, error) {
 	params := &apiclient.GetFamiliesV2FamiliesGetParams{ServerId: serverID}
 	resp, err := s.client.internalClient.GetFamiliesV2FamiliesGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 func (s *FamiliesService) Get(ctx context.Context, serverID, famID int) (*apiclient.FamilyResponse, error) {
+	body, _, err := s.GetWithResponse(ctx, serverID, famID)
+	return body, err
+}
+
+func (s *FamiliesService) GetWithResponse(ctx context.Context, serverID, famID int) (*apiclient.FamilyResponse, *Response, error) {
 	params := &apiclient.GetFamilyV2FamilyGetParams{ServerId: serverID, FamId: famID}
 	resp, err := s.client.internalClient.GetFamilyV2FamilyGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 type FractionsService service
 
 func (s *FractionsService) List(ctx context.Context, serverID int) (*apiclient.FractionsListResponse, error) {
+	body, _, err := s.ListWithResponse(ctx, serverID)
+	return body, err
+}
+
+func (s *FractionsService) ListWithResponse(ctx context.Context, serverID int) (*apiclient.FractionsListResponse, *Response, error) {
 	params := &apiclient.GetFractionsListV2FractionsGetParams{ServerId: serverID}
 	resp, err := s.client.internalClient.GetFractionsListV2FractionsGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 func (s *FractionsService) GetMembers(ctx context.Context, serverID int, fractionID string) (*apiclient.FractionResponse, error) {
+	body, _, err := s.GetMembersWithResponse(ctx, serverID, fractionID)
+	return body, err
+}
+
+func (s *FractionsService) GetMembersWithResponse(ctx context.Context, serverID int, fractionID string) (*apiclient.FractionResponse, *Response, error) {
 	params := &apiclient.GetFractionMembersV2FractionGetParams{ServerId: serverID, FractionId: fractionID}
 	resp, err := s.client.internalClient.GetFractionMembersV2FractionGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 type GhettoService service
 
 func (s *GhettoService) Get(ctx context.Context, serverID int) (*apiclient.GhettoResponse, error) {
+	body, _, err := s.GetWithResponse(ctx, serverID)
+	return body, err
+}
+
+func (s *GhettoService) GetWithResponse(ctx context.Context, serverID int) (*apiclient.GhettoResponse, *Response, error) {
 	params := &apiclient.GetGhettoListV2GhettoGetParams{ServerId: serverID}
 	resp, err := s.client.internalClient.GetGhettoListV2GhettoGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 type LeadershipService service
 
 func (s *LeadershipService) GetLeaders(ctx context.Context, serverID int) (*apiclient.LeadersResponse, error) {
+	body, _, err := s.GetLeadersWithResponse(ctx, serverID)
+	return body, err
+}
+
+func (s *LeadershipService) GetLeadersWithResponse(ctx context.Context, serverID int) (*apiclient.LeadersResponse, *Response, error) {
 	params := &apiclient.GetLeadersListV2LeadersGetParams{ServerId: serverID}
 	resp, err := s.client.internalClient.GetLeadersListV2LeadersGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 func (s *LeadershipService) GetSubleaders(ctx context.Context, serverID int) (*apiclient.SubleadersResponse, error) {
+	body, _, err := s.GetSubleadersWithResponse(ctx, serverID)
+	return body, err
+}
+
+func (s *LeadershipService) GetSubleadersWithResponse(ctx context.Context, serverID int) (*apiclient.SubleadersResponse, *Response, error) {
 	params := &apiclient.GetSubleadersListV2SubleadersGetParams{ServerId: serverID}
 	resp, err := s.client.internalClient.GetSubleadersListV2SubleadersGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 type MapService service
 
 func (s *MapService) Get(ctx context.Context, serverID int) (*apiclient.MapResponse, error) {
+	body, _, err := s.GetWithResponse(ctx, serverID)
+	return body, err
+}
+
+func (s *MapService) GetWithResponse(ctx context.Context, serverID int) (*apiclient.MapResponse, *Response, error) {
 	params := &apiclient.GetPropertyMapWithPoiV2MapGetParams{ServerId: serverID}
 	resp, err := s.client.internalClient.GetPropertyMapWithPoiV2MapGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 type OnlineService service
 
 func (s *OnlineService) Get(ctx context.Context, serverID int) (*apiclient.OnlinePlayersResponse, error) {
+	body, _, err := s.GetWithResponse(ctx, serverID)
+	return body, err
+}
+
+func (s *OnlineService) GetWithResponse(ctx context.Context, serverID int) (*apiclient.OnlinePlayersResponse, *Response, error) {
 	params := &apiclient.GetOnlineListV2OnlineGetParams{ServerId: serverID}
 	resp, err := s.client.internalClient.GetOnlineListV2OnlineGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 type PlayerService service
 
 func (s *PlayerService) Find(ctx context.Context, serverID int, nickname string) (*apiclient.PlayerResponse, error) {
+	body, _, err := s.FindWithResponse(ctx, serverID, nickname)
+	return body, err
+}
+
+func (s *PlayerService) FindWithResponse(ctx context.Context, serverID int, nickname string) (*apiclient.PlayerResponse, *Response, error) {
 	params := &apiclient.FindPlayerV2PlayerFindGetParams{ServerId: serverID, Nickname: nickname}
 	resp, err := s.client.internalClient.FindPlayerV2PlayerFindGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 type SobesService service
 
 func (s *SobesService) Get(ctx context.Context, serverID int) (*apiclient.SobesResponse, error) {
+	body, _, err := s.GetWithResponse(ctx, serverID)
+	return body, err
+}
+
+func (s *SobesService) GetWithResponse(ctx context.Context, serverID int) (*apiclient.SobesResponse, *Response, error) {
 	params := &apiclient.GetSobesListV2SobesGetParams{ServerId: serverID}
 	resp, err := s.client.internalClient.GetSobesListV2SobesGetWithResponse(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
 
 type StatusService service
 
 func (s *StatusService) Get(ctx context.Context) (*apiclient.StatusResponse, error) {
+	body, _, err := s.GetWithResponse(ctx)
+	return body, err
+}
+
+func (s *StatusService) GetWithResponse(ctx context.Context) (*apiclient.StatusResponse, *Response, error) {
 	resp, err := s.client.internalClient.GetStatusV2StatusGetWithResponse(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return unpackResponse(resp.JSON200, resp.HTTPResponse, err)
+	return unpackResponse(s.client, resp.JSON200, resp.Body, resp.HTTPResponse, err)
 }
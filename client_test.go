@@ -0,0 +1,108 @@
+package depsclient
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestNewClientDoesNotMutateCallerHTTPClient guards against the credential
+// leak this client had: NewClient installs an apiKey-injecting middleware on
+// its transport chain unconditionally, so if it aliased rather than copied a
+// caller-supplied *http.Client, that client's Transport would start
+// attaching X-API-Key to every future request made with it, including
+// unrelated requests to other hosts after Client construction.
+func TestNewClientDoesNotMutateCallerHTTPClient(t *testing.T) {
+	original := &http.Client{}
+
+	if _, err := NewClient("test-api-key", WithHTTPClient(original)); err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if original.Transport != nil {
+		t.Fatal("NewClient mutated the caller's *http.Client.Transport; it must only touch a copy")
+	}
+}
+
+// TestBuildTransportInjectsAPIKey exercises the actual transport chain
+// NewClient assembles: a request made through it must carry X-API-Key, and
+// the base transport it wraps must be left untouched.
+func TestBuildTransportInjectsAPIKey(t *testing.T) {
+	var gotHeader string
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		gotHeader = req.Header.Get(apiKeyHeader)
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+
+	cfg := &clientConfig{httpClient: &http.Client{Transport: base}}
+	transport := buildTransport(cfg, "test-api-key")
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if gotHeader != "test-api-key" {
+		t.Errorf("X-API-Key header = %q, want %q", gotHeader, "test-api-key")
+	}
+
+	// The base transport itself must not have been mutated or replaced: a
+	// request sent straight through cfg.httpClient.Transport must not pick up
+	// the X-API-Key header the chain injects. (roundTripperFunc is a func
+	// type, so the transports cannot be compared with != directly.)
+	gotHeader = ""
+	direct, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+	if _, err := cfg.httpClient.Transport.RoundTrip(direct); err != nil {
+		t.Fatalf("RoundTrip() on base transport error = %v", err)
+	}
+	if gotHeader != "" {
+		t.Error("buildTransport mutated cfg.httpClient.Transport in place")
+	}
+}
+
+func TestBuildTransportComposesRateLimitRetryAndMiddleware(t *testing.T) {
+	var calls int
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls < 2 {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}, Body: http.NoBody}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+
+	var loggedStatus int
+	logMW := Middleware(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if resp != nil {
+				loggedStatus = resp.StatusCode
+			}
+			return resp, err
+		})
+	})
+
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = 0
+	policy.MaxDelay = 0
+	policy.Jitter = 0
+
+	cfg := &clientConfig{
+		httpClient:  &http.Client{Transport: base},
+		retryPolicy: &policy,
+		middleware:  []Middleware{logMW},
+	}
+	transport := buildTransport(cfg, "test-api-key")
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want 200 after retry", resp.StatusCode)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 (one 503 retried into a 200)", calls)
+	}
+	if loggedStatus != http.StatusOK {
+		t.Errorf("middleware observed final status = %d, want 200", loggedStatus)
+	}
+}
@@ -0,0 +1,99 @@
+package depsclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors for programmatic handling via errors.Is. They match
+// against any *APIError with the corresponding status class.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServerError  = errors.New("server error")
+)
+
+// APIError is returned for any non-2xx response other than 404, which stays
+// ErrNotFound for backward compatibility, and 304, which resolves to the
+// cached body or ErrCacheMiss. It carries the parsed FastAPI-style
+// error envelope ({"detail": "...", "code": "..."}) alongside the raw body and
+// request context, so callers that need more than a string can get it.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Method     string
+	URL        string
+	RequestID  string
+	Code       string
+	Message    string
+	Details    map[string]any
+	RawBody    []byte
+
+	// RetryAfter is populated from the Retry-After header on 429 responses.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("api error: %s (status %s)", e.Message, e.Status)
+	}
+	return fmt.Sprintf("api error: status %s", e.Status)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= 500
+	default:
+		return false
+	}
+}
+
+type apiErrorEnvelope struct {
+	Detail  string         `json:"detail"`
+	Code    string         `json:"code"`
+	Details map[string]any `json:"details"`
+}
+
+// newAPIError builds an *APIError from a non-2xx httpResponse and the raw
+// body oapi-codegen already read off the wire. httpResponse.Body itself is
+// not read here: the generated ParseXxxResponse drains and closes it before
+// unpackResponse ever sees the response, leaving only the parsed Body []byte
+// field on the generated *apiclient.XxxResponse usable.
+func newAPIError(httpResponse *http.Response, rawBody []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: httpResponse.StatusCode,
+		Status:     httpResponse.Status,
+		RequestID:  httpResponse.Header.Get("X-Request-ID"),
+		RawBody:    rawBody,
+	}
+	if req := httpResponse.Request; req != nil {
+		apiErr.Method = req.Method
+		apiErr.URL = req.URL.String()
+	}
+
+	var envelope apiErrorEnvelope
+	if json.Unmarshal(rawBody, &envelope) == nil {
+		apiErr.Message = envelope.Detail
+		apiErr.Code = envelope.Code
+		apiErr.Details = envelope.Details
+	}
+
+	if httpResponse.StatusCode == http.StatusTooManyRequests {
+		if wait, ok := parseRetryAfter(httpResponse); ok {
+			apiErr.RetryAfter = wait
+		}
+	}
+
+	return apiErr
+}
@@ -0,0 +1,71 @@
+package depsclient
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestNewAPIErrorParsesFastAPIEnvelope(t *testing.T) {
+	rawBody := []byte(`{"detail": "server id not found", "code": "unknown_server", "details": {"server_id": 42.0}}`)
+	httpResponse := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Status:     "400 Bad Request",
+		Header:     http.Header{"X-Request-Id": []string{"req-123"}},
+	}
+
+	apiErr := newAPIError(httpResponse, rawBody)
+
+	if apiErr.Message != "server id not found" {
+		t.Errorf("Message = %q, want %q", apiErr.Message, "server id not found")
+	}
+	if apiErr.Code != "unknown_server" {
+		t.Errorf("Code = %q, want %q", apiErr.Code, "unknown_server")
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("RequestID = %q, want %q", apiErr.RequestID, "req-123")
+	}
+	if string(apiErr.RawBody) != string(rawBody) {
+		t.Errorf("RawBody = %q, want %q", apiErr.RawBody, rawBody)
+	}
+	if apiErr.Details["server_id"] != 42.0 {
+		t.Errorf("Details[server_id] = %v, want 42.0", apiErr.Details["server_id"])
+	}
+}
+
+func TestNewAPIErrorSentinelClassification(t *testing.T) {
+	tests := []struct {
+		status  int
+		sentCmp error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusInternalServerError, ErrServerError},
+	}
+
+	for _, tt := range tests {
+		httpResponse := &http.Response{
+			StatusCode: tt.status,
+			Status:     http.StatusText(tt.status),
+			Header:     http.Header{},
+		}
+		apiErr := newAPIError(httpResponse, nil)
+		if !errors.Is(apiErr, tt.sentCmp) {
+			t.Errorf("status %d: errors.Is(apiErr, %v) = false, want true", tt.status, tt.sentCmp)
+		}
+	}
+}
+
+func TestNewAPIErrorRetryAfterOnRateLimit(t *testing.T) {
+	httpResponse := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Status:     "429 Too Many Requests",
+		Header:     http.Header{"Retry-After": []string{"2"}},
+	}
+
+	apiErr := newAPIError(httpResponse, nil)
+	if apiErr.RetryAfter.Seconds() != 2 {
+		t.Errorf("RetryAfter = %v, want 2s", apiErr.RetryAfter)
+	}
+}
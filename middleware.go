@@ -0,0 +1,203 @@
+package depsclient
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware wraps an http.RoundTripper with another, so it can inspect or
+// modify every request/response passing through the Client's transport.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// WithMiddleware appends mw to the Client's transport chain, outermost last.
+// It composes with the transport built by WithRetry and WithRateLimit: the
+// chain wraps whatever those installed, so a logging or tracing middleware
+// still observes the outcome of retried requests.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(cfg *clientConfig) error {
+		cfg.middleware = append(cfg.middleware, mw...)
+		return nil
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// apiKeyMiddleware injects the API key used to authenticate against
+// depscian.tech. It is always the innermost middleware in the chain.
+func apiKeyMiddleware(apiKey string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set(apiKeyHeader, apiKey)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// LoggingMiddleware logs one structured line per request via logger, with
+// method, url, status, duration_ms, request_id and bytes attributes.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+
+			attrs := []any{
+				"method", req.Method,
+				"url", req.URL.String(),
+				"duration_ms", time.Since(start).Milliseconds(),
+			}
+			if resp != nil {
+				attrs = append(attrs,
+					"status", resp.StatusCode,
+					"request_id", resp.Header.Get("X-Request-ID"),
+					"bytes", resp.ContentLength,
+				)
+			}
+
+			if err != nil {
+				logger.Error("depsclient request failed", append(attrs, "error", err)...)
+			} else {
+				logger.Info("depsclient request", attrs...)
+			}
+
+			return resp, err
+		})
+	}
+}
+
+// TracingMiddleware starts an OpenTelemetry span per request, named
+// "depsclient.<service>.<method>" and derived from the request path.
+func TracingMiddleware(tracer trace.Tracer) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			service, method := splitOperation(req.URL.Path)
+			ctx, span := tracer.Start(req.Context(), "depsclient."+service+"."+method)
+			defer span.End()
+
+			req = req.WithContext(ctx)
+			span.SetAttributes(
+				attribute.String("http.method", req.Method),
+				attribute.String("http.url", req.URL.String()),
+			)
+
+			resp, err := next.RoundTrip(req)
+			if resp != nil {
+				span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+			}
+			if err != nil {
+				span.RecordError(err)
+			}
+			return resp, err
+		})
+	}
+}
+
+// MetricsMiddleware records depsclient_requests_total and
+// depsclient_request_duration_seconds for every request, registering both
+// collectors with registerer on first use. Passing nil registers against
+// prometheus.DefaultRegisterer. Unlike a package-level init(), this only
+// touches the registry when a caller actually opts into metrics, and it is
+// safe to call more than once (e.g. against the same registerer from
+// multiple Client instances): an already-registered collector is reused
+// instead of panicking via MustRegister.
+func MetricsMiddleware(registerer prometheus.Registerer) Middleware {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	requestsTotal := registerOrReuseCounterVec(registerer, prometheus.CounterOpts{
+		Name: "depsclient_requests_total",
+		Help: "Total number of depsclient requests, labeled by service, method and status.",
+	}, []string{"service", "method", "status"})
+
+	requestDuration := registerOrReuseHistogramVec(registerer, prometheus.HistogramOpts{
+		Name: "depsclient_request_duration_seconds",
+		Help: "Latency of depsclient requests in seconds, labeled by service and method.",
+	}, []string{"service", "method"})
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+
+			service, method := splitOperation(req.URL.Path)
+			requestDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+
+			status := "error"
+			if resp != nil {
+				status = strconv.Itoa(resp.StatusCode)
+			}
+			requestsTotal.WithLabelValues(service, method, status).Inc()
+
+			return resp, err
+		})
+	}
+}
+
+func registerOrReuseCounterVec(registerer prometheus.Registerer, opts prometheus.CounterOpts, labels []string) *prometheus.CounterVec {
+	vec := prometheus.NewCounterVec(opts, labels)
+	if err := registerer.Register(vec); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.CounterVec)
+		}
+		panic(err)
+	}
+	return vec
+}
+
+func registerOrReuseHistogramVec(registerer prometheus.Registerer, opts prometheus.HistogramOpts, labels []string) *prometheus.HistogramVec {
+	vec := prometheus.NewHistogramVec(opts, labels)
+	if err := registerer.Register(vec); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.HistogramVec)
+		}
+		panic(err)
+	}
+	return vec
+}
+
+// operation names every route this client talks to, since the API surface
+// is fixed and known ahead of time (see the service methods in client.go).
+// Keyed by URL path so it stays accurate for both single- and
+// multi-segment routes, unlike slicing the path into segments.
+type operation struct {
+	service string
+	method  string
+}
+
+var operationsByPath = map[string]operation{
+	"/v2/admins":      {"admins", "get"},
+	"/v2/families":    {"families", "list"},
+	"/v2/family":      {"families", "get"},
+	"/v2/fractions":   {"fractions", "list"},
+	"/v2/fraction":    {"fractions", "getMembers"},
+	"/v2/ghetto":      {"ghetto", "get"},
+	"/v2/leaders":     {"leadership", "getLeaders"},
+	"/v2/subleaders":  {"leadership", "getSubleaders"},
+	"/v2/map":         {"map", "get"},
+	"/v2/online":      {"online", "get"},
+	"/v2/player/find": {"player", "find"},
+	"/v2/sobes":       {"sobes", "get"},
+	"/v2/status":      {"status", "get"},
+}
+
+// splitOperation derives a (service, method) label pair from a request path,
+// e.g. "/v2/fractions" -> ("fractions", "list"). Paths this client never
+// issues (a custom WithBaseURL pointed somewhere else, for instance) fall
+// back to ("unknown", "unknown") rather than an empty method label.
+func splitOperation(path string) (service, method string) {
+	if op, ok := operationsByPath[path]; ok {
+		return op.service, op.method
+	}
+	return "unknown", "unknown"
+}
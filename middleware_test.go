@@ -0,0 +1,188 @@
+package depsclient
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestAPIKeyMiddlewareSetsHeader(t *testing.T) {
+	var got string
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header.Get(apiKeyHeader)
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	tripper := apiKeyMiddleware("secret-key")(base)
+	req, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+	if _, err := tripper.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if got != "secret-key" {
+		t.Errorf("%s header = %q, want %q", apiKeyHeader, got, "secret-key")
+	}
+}
+
+func TestLoggingMiddlewareLogsOutcome(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"X-Request-Id": {"req-1"}}, ContentLength: 123}, nil
+	})
+
+	tripper := LoggingMiddleware(logger)(base)
+	req, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+	if _, err := tripper.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"method=GET", "status=200", "request_id=req-1", "bytes=123"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output %q missing %q", out, want)
+		}
+	}
+}
+
+func TestLoggingMiddlewareLogsError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	wantErr := errors.New("boom")
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, wantErr
+	})
+
+	tripper := LoggingMiddleware(logger)(base)
+	req, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+	if _, err := tripper.RoundTrip(req); !errors.Is(err, wantErr) {
+		t.Fatalf("RoundTrip() error = %v, want %v", err, wantErr)
+	}
+	if !strings.Contains(buf.String(), "level=ERROR") {
+		t.Errorf("log output %q missing an ERROR level entry", buf.String())
+	}
+}
+
+func TestTracingMiddlewareRecordsStatusAndErrors(t *testing.T) {
+	tracer := trace.NewNoopTracerProvider().Tracer("depsclient-test")
+
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusTeapot}, nil
+	})
+	tripper := TracingMiddleware(tracer)(base)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+	resp, err := tripper.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusTeapot {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusTeapot)
+	}
+
+	failing := TracingMiddleware(tracer)(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("boom")
+	}))
+	if _, err := failing.RoundTrip(req); err == nil {
+		t.Error("expected the wrapped transport's error to propagate")
+	}
+}
+
+func TestMetricsMiddlewareRecordsRequestsAndDuration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	tripper := MetricsMiddleware(registry)(base)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+	if _, err := tripper.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	got := gatherCounterValue(t, registry, "depsclient_requests_total", map[string]string{
+		"service": "status", "method": "get", "status": "200",
+	})
+	if got != 1 {
+		t.Errorf("depsclient_requests_total{service=status,method=get,status=200} = %v, want 1", got)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	var sawHistogram bool
+	for _, mf := range families {
+		if mf.GetName() == "depsclient_request_duration_seconds" {
+			sawHistogram = true
+		}
+	}
+	if !sawHistogram {
+		t.Error("expected depsclient_request_duration_seconds to be collected alongside the counter")
+	}
+}
+
+// gatherCounterValue reads the value of the name metric carrying exactly the
+// given labels out of registry, failing the test if it isn't found.
+func gatherCounterValue(t *testing.T, registry *prometheus.Registry, name string, labels map[string]string) float64 {
+	t.Helper()
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, mf := range metrics {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			got := make(map[string]string, len(m.GetLabel()))
+			for _, l := range m.GetLabel() {
+				got[l.GetName()] = l.GetValue()
+			}
+			match := true
+			for k, v := range labels {
+				if got[k] != v {
+					match = false
+					break
+				}
+			}
+			if match {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	t.Fatalf("metric %s with labels %v not found", name, labels)
+	return 0
+}
+
+func TestSplitOperation(t *testing.T) {
+	tests := []struct {
+		path        string
+		wantService string
+		wantMethod  string
+	}{
+		{"/v2/admins", "admins", "get"},
+		{"/v2/families", "families", "list"},
+		{"/v2/family", "families", "get"},
+		{"/v2/fractions", "fractions", "list"},
+		{"/v2/fraction", "fractions", "getMembers"},
+		{"/v2/player/find", "player", "find"},
+		{"/v2/status", "status", "get"},
+		{"/unrecognized/path", "unknown", "unknown"},
+	}
+
+	for _, tt := range tests {
+		service, method := splitOperation(tt.path)
+		if service != tt.wantService || method != tt.wantMethod {
+			t.Errorf("splitOperation(%q) = (%q, %q), want (%q, %q)", tt.path, service, method, tt.wantService, tt.wantMethod)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package depsclient
+
+import (
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit caps outgoing requests to rps requests per second, allowing
+// bursts of up to burst, so callers don't need to build their own limiter to
+// stay under depscian.tech quotas. It is installed closest to the transport,
+// so every retry attempt from WithRetry is rate-limited individually.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(cfg *clientConfig) error {
+		cfg.rateLimiter = rate.NewLimiter(rate.Limit(rps), burst)
+		return nil
+	}
+}
+
+type rateLimitTripper struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *rateLimitTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
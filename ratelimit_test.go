@@ -0,0 +1,56 @@
+package depsclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitTripperForwardsEveryRequest(t *testing.T) {
+	var calls int
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+
+	tripper := &rateLimitTripper{next: base, limiter: rate.NewLimiter(rate.Limit(1000), 2)}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+	for i := 0; i < 3; i++ {
+		if _, err := tripper.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip() #%d error = %v", i, err)
+		}
+	}
+
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRateLimitTripperRespectsContextCancellation(t *testing.T) {
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		t.Fatal("next.RoundTrip called despite an exhausted limiter and cancelled context")
+		return nil, nil
+	})
+
+	// A limiter with a burst of one and a rate far slower than the test's deadline
+	// ensures Wait blocks until the context is cancelled.
+	tripper := &rateLimitTripper{next: base, limiter: rate.NewLimiter(rate.Limit(0.001), 1)}
+	// Drain the single burst token first so the next Wait actually blocks.
+	_ = tripper.limiter.Allow()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	if _, err := tripper.RoundTrip(req); err == nil {
+		t.Error("RoundTrip() error = nil, want context cancellation error")
+	}
+}
@@ -0,0 +1,37 @@
+package depsclient
+
+import "net/http"
+
+// Response carries the HTTP-level metadata for a single API call, mirroring
+// the pattern used by Mattermost's Client4: every service method keeps the
+// decoded body as its primary return value, but callers that need to log
+// request IDs, inspect rate-limit headers, or act on ETags can opt into the
+// accompanying *Response via the WithResponse variant of that method.
+type Response struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+
+	// RequestID is populated from the X-Request-ID response header, if present.
+	RequestID string
+
+	// ETag is populated from the ETag response header, if present.
+	ETag string
+
+	// ServerVersion is populated from the X-Server-Version response header, if present.
+	ServerVersion string
+}
+
+func buildResponse(httpResponse *http.Response) *Response {
+	if httpResponse == nil {
+		return nil
+	}
+	return &Response{
+		StatusCode:    httpResponse.StatusCode,
+		Status:        httpResponse.Status,
+		Header:        httpResponse.Header,
+		RequestID:     httpResponse.Header.Get("X-Request-ID"),
+		ETag:          httpResponse.Header.Get("ETag"),
+		ServerVersion: httpResponse.Header.Get("X-Server-Version"),
+	}
+}
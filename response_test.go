@@ -0,0 +1,56 @@
+package depsclient
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBuildResponse(t *testing.T) {
+	httpResponse := &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header: http.Header{
+			"X-Request-Id":     []string{"req-42"},
+			"Etag":             []string{`"abc123"`},
+			"X-Server-Version": []string{"1.2.3"},
+		},
+	}
+
+	resp := buildResponse(httpResponse)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if resp.Status != "200 OK" {
+		t.Errorf("Status = %q, want %q", resp.Status, "200 OK")
+	}
+	if resp.RequestID != "req-42" {
+		t.Errorf("RequestID = %q, want %q", resp.RequestID, "req-42")
+	}
+	if resp.ETag != `"abc123"` {
+		t.Errorf("ETag = %q, want %q", resp.ETag, `"abc123"`)
+	}
+	if resp.ServerVersion != "1.2.3" {
+		t.Errorf("ServerVersion = %q, want %q", resp.ServerVersion, "1.2.3")
+	}
+}
+
+func TestBuildResponseMissingHeaders(t *testing.T) {
+	httpResponse := &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{},
+	}
+
+	resp := buildResponse(httpResponse)
+
+	if resp.RequestID != "" || resp.ETag != "" || resp.ServerVersion != "" {
+		t.Errorf("expected empty optional fields, got %+v", resp)
+	}
+}
+
+func TestBuildResponseNil(t *testing.T) {
+	if resp := buildResponse(nil); resp != nil {
+		t.Errorf("buildResponse(nil) = %+v, want nil", resp)
+	}
+}
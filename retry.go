@@ -0,0 +1,142 @@
+package depsclient
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures the exponential-backoff retry tripper installed by
+// WithRetry. RetryOn decides whether a given response/error pair should be
+// retried; it defaults to network errors, 429, and 5xx.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Multiplier  float64
+	Jitter      float64
+	RetryOn     func(*http.Response, error) bool
+}
+
+// DefaultRetryPolicy returns the policy whose values WithRetry substitutes
+// for unset fields: up to 3 attempts, doubling from 500ms and capped at 10s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Multiplier:  2,
+		Jitter:      0.2,
+		RetryOn:     defaultRetryOn,
+	}
+}
+
+func defaultRetryOn(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// WithRetry installs policy as an http.RoundTripper wrapper around the
+// client's transport, so every generated apiclient.*WithResponse call is
+// retried uniformly. Zero-valued (or negative) MaxAttempts, BaseDelay,
+// MaxDelay and Multiplier, and a nil RetryOn, fall back to
+// DefaultRetryPolicy's; Jitter is kept as given, so zero disables jitter.
+func WithRetry(policy RetryPolicy) Option {
+	return func(cfg *clientConfig) error {
+		def := DefaultRetryPolicy()
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = def.MaxAttempts
+		}
+		if policy.BaseDelay <= 0 {
+			policy.BaseDelay = def.BaseDelay
+		}
+		if policy.MaxDelay <= 0 {
+			policy.MaxDelay = def.MaxDelay
+		}
+		if policy.Multiplier <= 0 {
+			policy.Multiplier = def.Multiplier
+		}
+		if policy.RetryOn == nil {
+			policy.RetryOn = def.RetryOn
+		}
+		cfg.retryPolicy = &policy
+		return nil
+	}
+}
+
+type retryTripper struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var bodyBytes []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		bodyBytes = b
+	}
+
+	delay := t.policy.BaseDelay
+
+	for attempt := 1; ; attempt++ {
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		resp, err := t.next.RoundTrip(req)
+
+		if !t.policy.RetryOn(resp, err) || attempt >= t.policy.MaxAttempts {
+			return resp, err
+		}
+
+		wait := delay
+		if resp != nil {
+			if ra, ok := parseRetryAfter(resp); ok {
+				wait = ra
+			}
+			resp.Body.Close()
+		}
+		if t.policy.Jitter > 0 {
+			wait += time.Duration(rand.Float64() * t.policy.Jitter * float64(wait))
+		}
+		if wait > t.policy.MaxDelay {
+			wait = t.policy.MaxDelay
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		delay = time.Duration(float64(delay) * t.policy.Multiplier)
+	}
+}
+
+// parseRetryAfter parses the Retry-After header in both its seconds and
+// HTTP-date forms.
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
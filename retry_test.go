@@ -0,0 +1,160 @@
+package depsclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfterSeconds(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"5"}}}
+
+	wait, ok := parseRetryAfter(resp)
+	if !ok {
+		t.Fatal("parseRetryAfter() ok = false, want true")
+	}
+	if wait != 5*time.Second {
+		t.Errorf("parseRetryAfter() = %v, want 5s", wait)
+	}
+}
+
+func TestParseRetryAfterHTTPDate(t *testing.T) {
+	future := time.Now().Add(10 * time.Second).UTC()
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{future.Format(http.TimeFormat)}}}
+
+	wait, ok := parseRetryAfter(resp)
+	if !ok {
+		t.Fatal("parseRetryAfter() ok = false, want true")
+	}
+	if wait <= 0 || wait > 11*time.Second {
+		t.Errorf("parseRetryAfter() = %v, want roughly 10s", wait)
+	}
+}
+
+func TestParseRetryAfterMissing(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	if _, ok := parseRetryAfter(resp); ok {
+		t.Error("parseRetryAfter() ok = true for missing header, want false")
+	}
+}
+
+func TestDefaultRetryOn(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{"network error", nil, errors.New("dial tcp: timeout"), true},
+		{"429", &http.Response{StatusCode: http.StatusTooManyRequests}, nil, true},
+		{"503", &http.Response{StatusCode: http.StatusServiceUnavailable}, nil, true},
+		{"200", &http.Response{StatusCode: http.StatusOK}, nil, false},
+		{"404", &http.Response{StatusCode: http.StatusNotFound}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultRetryOn(tt.resp, tt.err); got != tt.want {
+				t.Errorf("defaultRetryOn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// stubTripper returns responses[i] (or err) on its i-th call, then keeps
+// returning the last entry for any call beyond len(responses).
+type stubTripper struct {
+	responses []int
+	err       error
+	calls     int
+}
+
+func (s *stubTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := s.calls
+	if i >= len(s.responses) {
+		i = len(s.responses) - 1
+	}
+	s.calls++
+	if s.err != nil && i == len(s.responses)-1 {
+		return nil, s.err
+	}
+	return &http.Response{
+		StatusCode: s.responses[i],
+		Status:     http.StatusText(s.responses[i]),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Header:     http.Header{},
+	}, nil
+}
+
+func TestRetryTripperRetriesUntilSuccess(t *testing.T) {
+	stub := &stubTripper{responses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK}}
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 5 * time.Millisecond
+	policy.Jitter = 0
+
+	tripper := &retryTripper{next: stub, policy: policy}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+
+	resp, err := tripper.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want 200", resp.StatusCode)
+	}
+	if stub.calls != 3 {
+		t.Errorf("calls = %d, want 3", stub.calls)
+	}
+}
+
+func TestRetryTripperStopsAtMaxAttempts(t *testing.T) {
+	stub := &stubTripper{responses: []int{
+		http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+	}}
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 2
+	policy.BaseDelay = time.Millisecond
+	policy.Jitter = 0
+
+	tripper := &retryTripper{next: stub, policy: policy}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+
+	resp, err := tripper.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("final status = %d, want 503", resp.StatusCode)
+	}
+	if stub.calls != 2 {
+		t.Errorf("calls = %d, want 2 (capped by MaxAttempts)", stub.calls)
+	}
+}
+
+func TestRetryTripperRespectsContextCancellation(t *testing.T) {
+	stub := &stubTripper{responses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}}
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 5
+	policy.BaseDelay = time.Hour
+	policy.Jitter = 0
+
+	tripper := &retryTripper{next: stub, policy: policy}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.depscian.tech/v2/status", nil)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := tripper.RoundTrip(req)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("RoundTrip() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,136 @@
+package depsclient
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type testBody struct {
+	Value string
+}
+
+func newTestRequest() *http.Request {
+	return &http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{Scheme: "https", Host: "api.depscian.tech", Path: "/v2/status"},
+	}
+}
+
+func TestUnpackResponseStoresAndResolvesFromCache(t *testing.T) {
+	c := &Client{cache: NewMemoryCache(10)}
+	req := newTestRequest()
+	body := &testBody{Value: "first"}
+
+	okResponse := &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Etag": {`"v1"`}},
+		Request:    req,
+	}
+
+	got, resp, err := unpackResponse(c, body, nil, okResponse, nil)
+	if err != nil {
+		t.Fatalf("unpackResponse() error = %v", err)
+	}
+	if got != body {
+		t.Fatalf("unpackResponse() body = %v, want %v", got, body)
+	}
+	if resp.ETag != `"v1"` {
+		t.Fatalf("resp.ETag = %q, want %q", resp.ETag, `"v1"`)
+	}
+
+	notModified := &http.Response{
+		StatusCode: http.StatusNotModified,
+		Status:     "304 Not Modified",
+		Header:     http.Header{},
+		Request:    req,
+	}
+
+	cached, _, err := unpackResponse[testBody](c, nil, nil, notModified, nil)
+	if err != nil {
+		t.Fatalf("unpackResponse() on 304 error = %v", err)
+	}
+	if cached != body {
+		t.Fatalf("unpackResponse() on 304 = %v, want the cached %v", cached, body)
+	}
+}
+
+func TestUnpackResponseCacheMissOn304(t *testing.T) {
+	c := &Client{cache: NewMemoryCache(10)}
+	req := newTestRequest()
+
+	notModified := &http.Response{
+		StatusCode: http.StatusNotModified,
+		Status:     "304 Not Modified",
+		Header:     http.Header{},
+		Request:    req,
+	}
+
+	_, _, err := unpackResponse[testBody](c, nil, nil, notModified, nil)
+	if !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("unpackResponse() error = %v, want ErrCacheMiss", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("a cache-miss 304 must not also satisfy errors.Is(err, ErrNotFound)")
+	}
+}
+
+func TestUnpackResponseWithoutCache304IsCacheMiss(t *testing.T) {
+	c := &Client{}
+	req := newTestRequest()
+
+	notModified := &http.Response{
+		StatusCode: http.StatusNotModified,
+		Status:     "304 Not Modified",
+		Header:     http.Header{},
+		Request:    req,
+	}
+
+	_, _, err := unpackResponse[testBody](c, nil, nil, notModified, nil)
+	if !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("unpackResponse() error = %v, want ErrCacheMiss", err)
+	}
+}
+
+func TestUnpackResponseNotFound(t *testing.T) {
+	c := &Client{}
+	resp := &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Header: http.Header{}, Request: newTestRequest()}
+
+	_, _, err := unpackResponse[testBody](c, nil, nil, resp, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("unpackResponse() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUnpackResponseAPIError(t *testing.T) {
+	c := &Client{}
+	rawBody := []byte(`{"detail": "boom", "code": "oops"}`)
+	resp := &http.Response{StatusCode: http.StatusInternalServerError, Status: "500 Internal Server Error", Header: http.Header{}, Request: newTestRequest()}
+
+	_, _, err := unpackResponse[testBody](c, nil, rawBody, resp, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("unpackResponse() error = %v, want *APIError", err)
+	}
+	if apiErr.Message != "boom" || apiErr.Code != "oops" {
+		t.Errorf("apiErr = %+v, want Message=boom Code=oops", apiErr)
+	}
+	if !errors.Is(err, ErrServerError) {
+		t.Error("expected errors.Is(err, ErrServerError) to hold for a 500")
+	}
+}
+
+func TestUnpackResponseClientExecutionError(t *testing.T) {
+	c := &Client{}
+	wantErr := errors.New("dial tcp: boom")
+
+	_, resp, err := unpackResponse[testBody](c, nil, nil, nil, wantErr)
+	if resp != nil {
+		t.Errorf("resp = %+v, want nil", resp)
+	}
+	if !errors.Is(err, wantErr) {
+		t.Errorf("unpackResponse() error = %v, want wrapping %v", err, wantErr)
+	}
+}